@@ -0,0 +1,180 @@
+package simplexlog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy controls what happens to a record when an Async Logger's buffer is full
+type DropPolicy int
+
+const (
+	// Block waits for room in the buffer, this is the default and matches the non-Async behavior
+	Block DropPolicy = iota
+	// DropOldest discards the oldest buffered record to make room for the new one
+	DropOldest
+	// DropNewest discards the incoming record instead of waiting for room
+	DropNewest
+)
+
+// asyncRecord is a single log call, already fully rendered (fields/caller/stack/format applied, and for
+// TextFormat the date/time header baked in, see output's textHeader call) at call time, queued for a
+// background goroutine to write. A record with a non-nil done is a flush barrier: it carries no write, and
+// the dispatcher just closes done once every record ahead of it has been written.
+type asyncRecord struct {
+	write func()
+	done  chan struct{}
+}
+
+// asyncDispatcher owns the bounded channel and worker goroutine backing a Logger's Async mode
+type asyncDispatcher struct {
+	ch        chan asyncRecord
+	policy    DropPolicy
+	dropped   uint64
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func newAsyncDispatcher(bufferSize int, policy DropPolicy) *asyncDispatcher {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+
+	d := &asyncDispatcher{
+		ch:     make(chan asyncRecord, bufferSize),
+		policy: policy,
+	}
+
+	d.wg.Add(1)
+	go d.drain()
+
+	return d
+}
+
+func (d *asyncDispatcher) drain() {
+	defer d.wg.Done()
+
+	for rec := range d.ch {
+		if rec.done != nil {
+			close(rec.done)
+			continue
+		}
+		rec.write()
+	}
+}
+
+// enqueue hands a write to the background goroutine, applying the dispatcher's DropPolicy if the buffer is full
+func (d *asyncDispatcher) enqueue(write func()) {
+	rec := asyncRecord{write: write}
+
+	switch d.policy {
+	case DropNewest:
+		select {
+		case d.ch <- rec:
+		default:
+			atomic.AddUint64(&d.dropped, 1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case d.ch <- rec:
+				return
+			default:
+			}
+			select {
+			case <-d.ch:
+				atomic.AddUint64(&d.dropped, 1)
+			default:
+			}
+		}
+	default: // Block
+		d.ch <- rec
+	}
+}
+
+// flush blocks until every record enqueued before the call has been written, or ctx is done
+func (d *asyncDispatcher) flush(ctx context.Context) error {
+	done := make(chan struct{})
+
+	select {
+	case d.ch <- asyncRecord{done: done}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *asyncDispatcher) close() error {
+	d.closeOnce.Do(func() { close(d.ch) })
+	d.wg.Wait()
+
+	return nil
+}
+
+func (d *asyncDispatcher) droppedCount() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// applyAsync lazily creates l's async dispatcher the first time a Config with Async set is applied; later
+// Set* calls with Async set again are a no-op for an already-async Logger, since there's a single dispatcher.
+func (l *Logger) applyAsync(c Config) {
+	if !c.Async || l.async != nil {
+		return
+	}
+
+	l.async = newAsyncDispatcher(c.BufferSize, c.DropPolicy)
+}
+
+// Flush blocks until every record enqueued before the call has been written, or ctx is done. It is a no-op
+// returning nil when l is not in Async mode.
+func (l *Logger) Flush(ctx context.Context) error {
+	if l.async == nil {
+		return nil
+	}
+
+	return l.async.flush(ctx)
+}
+
+// Close stops l's background dispatch goroutine after writing every record already enqueued. It is a no-op
+// returning nil when l is not in Async mode.
+func (l *Logger) Close() error {
+	if l.async == nil {
+		return nil
+	}
+
+	return l.async.close()
+}
+
+// Dropped return the number of records discarded because the Async buffer was full under DropOldest or
+// DropNewest, it is always 0 when l is not in Async mode or uses the Block policy.
+func (l *Logger) Dropped() uint64 {
+	if l.async == nil {
+		return 0
+	}
+
+	return l.async.droppedCount()
+}
+
+// Sync return a child logger that writes synchronously, bypassing l's Async dispatch if any. It carries l's
+// fields and shares l's outputs and log level, like a logger returned by Fields/With.
+func (l *Logger) Sync() *Logger {
+	child := *l
+	child.async = nil
+
+	return &child
+}
+
+// flushAsync blocks until the record just enqueued by output has been written, a no-op when l is not in
+// Async mode. Fatal/Panic call this before os.Exit/panic so the final record is never lost in the buffer.
+func (l *Logger) flushAsync() {
+	if l.async != nil {
+		l.async.flush(context.Background())
+	}
+}
@@ -0,0 +1,92 @@
+package simplexlog
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAsyncFlushDrainsBeforeReturning checks that Flush only returns once every record enqueued before the
+// call has actually reached the underlying writer.
+func TestAsyncFlushDrainsBeforeReturning(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(SetAllDefault(Config{Out: &buf, Async: true, BufferSize: 8}))
+
+	for i := 0; i < 5; i++ {
+		l.Infof("record %d", i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if !strings.Contains(buf.String(), "record "+string(rune('0'+i))) {
+			t.Fatalf("record %d missing after Flush, got %q", i, buf.String())
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+// TestAsyncDropPolicyCounts checks that a full buffer under DropNewest/DropOldest discards records instead
+// of blocking, and that Dropped reports the discarded count.
+func TestAsyncDropPolicyCounts(t *testing.T) {
+	for _, policy := range []DropPolicy{DropNewest, DropOldest} {
+		var buf bytes.Buffer
+		l := New(SetAllDefault(Config{Out: &buf, Async: true, BufferSize: 1, DropPolicy: policy}))
+
+		for i := 0; i < 50; i++ {
+			l.Info("burst")
+		}
+
+		if err := l.Flush(context.Background()); err != nil {
+			t.Fatalf("policy %v: Flush: %v", policy, err)
+		}
+		if l.Dropped() == 0 {
+			t.Fatalf("policy %v: expected some records to be dropped under a full buffer", policy)
+		}
+
+		l.Close()
+	}
+}
+
+// TestFatalAsyncWritesBeforeExit reproduces the report of an Async logger's Fatal record being lost because
+// Fatal called os.Exit before the background goroutine drained the enqueued write. It runs Fatal in a
+// subprocess, since Fatal itself calls os.Exit(1).
+func TestFatalAsyncWritesBeforeExit(t *testing.T) {
+	if os.Getenv("SIMPLEXLOG_TEST_FATAL_ASYNC") == "1" {
+		f, err := os.Create(os.Getenv("SIMPLEXLOG_TEST_FATAL_ASYNC_FILE"))
+		if err != nil {
+			os.Exit(2)
+		}
+		l := New(SetAllDefault(Config{Out: f, Async: true, BufferSize: 8}))
+		l.Fatal("dying message")
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "fatal.log")
+	cmd := exec.Command(os.Args[0], "-test.run=TestFatalAsyncWritesBeforeExit")
+	cmd.Env = append(os.Environ(),
+		"SIMPLEXLOG_TEST_FATAL_ASYNC=1",
+		"SIMPLEXLOG_TEST_FATAL_ASYNC_FILE="+path,
+	)
+	_ = cmd.Run()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading subprocess log file: %v", err)
+	}
+	if !strings.Contains(string(data), "dying message") {
+		t.Fatalf("Fatal record lost under Async mode, got %q", data)
+	}
+}
@@ -0,0 +1,57 @@
+package simplexlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowWriter blocks the first Write for delay, simulating a slow sink a record sits queued behind while
+// waiting to be drained.
+type slowWriter struct {
+	buf   bytes.Buffer
+	delay time.Duration
+	first bool
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	if !w.first {
+		w.first = true
+		time.Sleep(w.delay)
+	}
+	return w.buf.Write(p)
+}
+
+// TestAsyncTextTimestampIsCallTime checks that a TextFormat record logged under Async carries the
+// timestamp from when it was logged, not from whenever the drain goroutine got around to writing it.
+func TestAsyncTextTimestampIsCallTime(t *testing.T) {
+	sw := &slowWriter{delay: 300 * time.Millisecond}
+	l := New(SetAllDefault(Config{Out: sw, Async: true, BufferSize: 8, Flags: DefaultLogFlags}))
+
+	// Prime the dispatcher with a slow first write so this second record sits queued during drain.
+	l.Info("priming")
+	callTime := time.Now()
+	l.Info("timed")
+
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(sw.buf.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected 2 lines, got %q", sw.buf.String())
+	}
+	timedLine := lines[1]
+
+	stamp := timedLine[:len("2006/01/02 15:04:05.000000")]
+	parsed, err := time.ParseInLocation("2006/01/02 15:04:05.000000", stamp, time.Local)
+	if err != nil {
+		t.Fatalf("parsing stamp %q: %v", stamp, err)
+	}
+
+	if drift := parsed.Sub(callTime); drift < -50*time.Millisecond || drift > 50*time.Millisecond {
+		t.Fatalf("timestamp drifted from call time by %v (call=%v logged=%v)", drift, callTime, parsed)
+	}
+}
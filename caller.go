@@ -0,0 +1,33 @@
+package simplexlog
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// callerBaseSkip is the number of stack frames between l.caller and the user's call to one of Logger's
+// public log methods (l.caller -> output -> Infof/Info/...), before any user-supplied CallerSkip is added.
+const callerBaseSkip = 3
+
+// maxStackSize bounds the runtime.Stack snapshot captured when StackTraces is set
+const maxStackSize = 8 * 1024
+
+// caller return the "file:line" of the code that called one of Logger's public log methods, honoring
+// CallerSkip for callers that wrap Logger in their own helper functions. It bypasses log.Logger's own
+// Lshortfile/Llongfile, which would otherwise point at simplexlog itself.
+func (l *Logger) caller() (string, bool) {
+	_, file, line, ok := runtime.Caller(callerBaseSkip + l.callerSkip)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s:%d", file, line), true
+}
+
+// captureStack return a bounded snapshot of the current goroutine's stack
+func captureStack() string {
+	buf := make([]byte, maxStackSize)
+	n := runtime.Stack(buf, false)
+
+	return string(buf[:n])
+}
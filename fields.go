@@ -0,0 +1,37 @@
+package simplexlog
+
+// Fields is a set of structured key/value pairs carried by a Logger and emitted with every log call:
+// as extra keys in the JSON record when Format is JSONFormat, or as trailing "key=value" pairs otherwise.
+type Fields map[string]interface{}
+
+// Fields return a child logger that carries fields in addition to any fields already set on l.
+// The child shares l's outputs and log level (SwitchTo on the parent is seen by the child), but fields
+// are copied into the child so later changes on either logger don't race with each other.
+func (l *Logger) Fields(fields map[string]interface{}) *Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	child := *l
+	child.fields = merged
+
+	return &child
+}
+
+// With return a child logger that carries the single field key=value, see Fields
+func (l *Logger) With(key string, value interface{}) *Logger {
+	return l.Fields(Fields{key: value})
+}
+
+// WithError return a child logger that carries err under the "error" field, see Fields
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l.With("error", nil)
+	}
+
+	return l.With("error", err.Error())
+}
@@ -0,0 +1,286 @@
+package simplexlog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LevelLogger is the logging surface shared by *Logger and *Filter, so a Filter can be substituted
+// transparently wherever a *Logger was expected.
+type LevelLogger interface {
+	Infof(format string, v ...interface{})
+	Noticef(format string, v ...interface{})
+	Warningf(format string, v ...interface{})
+	Errorf(format string, v ...interface{})
+	Criticalf(format string, v ...interface{})
+	Debugf(format string, v ...interface{})
+	Tracef(format string, v ...interface{})
+	Fatalf(format string, v ...interface{})
+	Panicf(format string, v ...interface{})
+	Info(a ...interface{})
+	Notice(a ...interface{})
+	Warning(a ...interface{})
+	Error(a ...interface{})
+	Critical(a ...interface{})
+	Debug(a ...interface{})
+	Trace(a ...interface{})
+	Fatal(a ...interface{})
+	Panic(a ...interface{})
+	SwitchTo(level interface{})
+	Level() LogLevel
+	LevelName() string
+}
+
+var (
+	_ LevelLogger = (*Logger)(nil)
+	_ LevelLogger = (*Filter)(nil)
+)
+
+// FilterOption configures a Filter, see NewFilter
+type FilterOption func(*Filter)
+
+// FilterLevel drops any record more verbose than level, regardless of the wrapped Logger's own level
+func FilterLevel(level LogLevel) FilterOption {
+	return func(f *Filter) {
+		f.level = level
+		f.hasLevel = true
+	}
+}
+
+// FilterKey masks the named structured fields (set with Fields/With/WithError) with "***" before they
+// reach the wrapped Logger
+func FilterKey(keys ...string) FilterOption {
+	return func(f *Filter) {
+		for _, k := range keys {
+			f.keys[k] = struct{}{}
+		}
+	}
+}
+
+// FilterValue masks any occurrence of the given literal substrings in a formatted message with "***"
+func FilterValue(values ...string) FilterOption {
+	return func(f *Filter) {
+		f.values = append(f.values, values...)
+	}
+}
+
+// FilterFunc drops a record whenever fn returns true; fn receives the level, the format string (empty for
+// the non-f variants like Info/Error) and the arguments passed to the call
+func FilterFunc(fn func(level LogLevel, format string, args []interface{}) bool) FilterOption {
+	return func(f *Filter) {
+		f.filterFunc = fn
+	}
+}
+
+// Filter wraps a *Logger to redact secrets/PII (by key or literal value) or drop records before they
+// reach the underlying writers. It implements LevelLogger so it can be used anywhere a *Logger was.
+type Filter struct {
+	logger     *Logger
+	level      LogLevel
+	hasLevel   bool
+	keys       map[string]struct{}
+	values     []string
+	filterFunc func(level LogLevel, format string, args []interface{}) bool
+}
+
+// NewFilter return a Filter wrapping l, configured by opts (FilterLevel, FilterKey, FilterValue, FilterFunc)
+func NewFilter(l *Logger, opts ...FilterOption) *Filter {
+	f := &Filter{
+		logger: l,
+		keys:   make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// dropped report whether the record must not reach the wrapped Logger
+func (f *Filter) dropped(level LogLevel, format string, args []interface{}) bool {
+	if f.hasLevel && level > f.level {
+		return true
+	}
+
+	return f.filterFunc != nil && f.filterFunc(level, format, args)
+}
+
+// maskMessage replaces every occurrence of a FilterValue literal in msg with "***"
+func (f *Filter) maskMessage(msg string) string {
+	for _, v := range f.values {
+		if v == "" {
+			continue
+		}
+		msg = strings.ReplaceAll(msg, v, "***")
+	}
+
+	return msg
+}
+
+// maskedLogger return the wrapped Logger, or a child of it with any FilterKey field replaced by "***". When
+// the wrapped Logger has IncludeCaller set, the child's CallerSkip is bumped by one to account for the extra
+// Filter.Infof/Info/... stack frame between the caller and the wrapped Logger's own output method, so
+// caller() still attributes the record to the user's call site instead of to filter.go.
+func (f *Filter) maskedLogger() *Logger {
+	needsMasking := len(f.keys) > 0 && len(f.logger.fields) > 0
+	if !f.logger.includeCaller && !needsMasking {
+		return f.logger
+	}
+
+	child := *f.logger
+	if f.logger.includeCaller {
+		child.callerSkip++
+	}
+
+	if needsMasking {
+		masked := make(Fields, len(f.logger.fields))
+		for k, v := range f.logger.fields {
+			if _, match := f.keys[k]; match {
+				masked[k] = "***"
+				continue
+			}
+			masked[k] = v
+		}
+		child.fields = masked
+	}
+
+	return &child
+}
+
+func (f *Filter) Infof(format string, v ...interface{}) {
+	if f.dropped(Info, format, v) {
+		return
+	}
+	f.maskedLogger().Infof("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+func (f *Filter) Noticef(format string, v ...interface{}) {
+	if f.dropped(Notice, format, v) {
+		return
+	}
+	f.maskedLogger().Noticef("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+func (f *Filter) Warningf(format string, v ...interface{}) {
+	if f.dropped(Warning, format, v) {
+		return
+	}
+	f.maskedLogger().Warningf("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+func (f *Filter) Errorf(format string, v ...interface{}) {
+	if f.dropped(Error, format, v) {
+		return
+	}
+	f.maskedLogger().Errorf("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+func (f *Filter) Criticalf(format string, v ...interface{}) {
+	if f.dropped(Critical, format, v) {
+		return
+	}
+	f.maskedLogger().Criticalf("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+func (f *Filter) Debugf(format string, v ...interface{}) {
+	if f.dropped(Debug, format, v) {
+		return
+	}
+	f.maskedLogger().Debugf("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+func (f *Filter) Tracef(format string, v ...interface{}) {
+	if f.dropped(Trace, format, v) {
+		return
+	}
+	f.maskedLogger().Tracef("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+// Fatalf always reaches the wrapped Logger (and so still calls os.Exit(1)), FilterLevel/FilterFunc do not
+// apply to it, only FilterKey/FilterValue masking does
+func (f *Filter) Fatalf(format string, v ...interface{}) {
+	f.maskedLogger().Fatalf("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+// Panicf always reaches the wrapped Logger (and so still calls panic), FilterLevel/FilterFunc do not apply
+// to it, only FilterKey/FilterValue masking does
+func (f *Filter) Panicf(format string, v ...interface{}) {
+	f.maskedLogger().Panicf("%s", f.maskMessage(fmt.Sprintf(format, v...)))
+}
+
+func (f *Filter) Info(a ...interface{}) {
+	if f.dropped(Info, "", a) {
+		return
+	}
+	f.maskedLogger().Info(f.maskMessage(fmt.Sprint(a...)))
+}
+
+func (f *Filter) Notice(a ...interface{}) {
+	if f.dropped(Notice, "", a) {
+		return
+	}
+	f.maskedLogger().Notice(f.maskMessage(fmt.Sprint(a...)))
+}
+
+func (f *Filter) Warning(a ...interface{}) {
+	if f.dropped(Warning, "", a) {
+		return
+	}
+	f.maskedLogger().Warning(f.maskMessage(fmt.Sprint(a...)))
+}
+
+func (f *Filter) Error(a ...interface{}) {
+	if f.dropped(Error, "", a) {
+		return
+	}
+	f.maskedLogger().Error(f.maskMessage(fmt.Sprint(a...)))
+}
+
+func (f *Filter) Critical(a ...interface{}) {
+	if f.dropped(Critical, "", a) {
+		return
+	}
+	f.maskedLogger().Critical(f.maskMessage(fmt.Sprint(a...)))
+}
+
+func (f *Filter) Debug(a ...interface{}) {
+	if f.dropped(Debug, "", a) {
+		return
+	}
+	f.maskedLogger().Debug(f.maskMessage(fmt.Sprint(a...)))
+}
+
+func (f *Filter) Trace(a ...interface{}) {
+	if f.dropped(Trace, "", a) {
+		return
+	}
+	f.maskedLogger().Trace(f.maskMessage(fmt.Sprint(a...)))
+}
+
+// Fatal always reaches the wrapped Logger (and so still calls os.Exit(1)), FilterLevel/FilterFunc do not
+// apply to it, only FilterKey/FilterValue masking does
+func (f *Filter) Fatal(a ...interface{}) {
+	f.maskedLogger().Fatal(f.maskMessage(fmt.Sprint(a...)))
+}
+
+// Panic always reaches the wrapped Logger (and so still calls panic), FilterLevel/FilterFunc do not apply
+// to it, only FilterKey/FilterValue masking does
+func (f *Filter) Panic(a ...interface{}) {
+	f.maskedLogger().Panic(f.maskMessage(fmt.Sprint(a...)))
+}
+
+// SwitchTo change the level of the wrapped Logger, see Logger.SwitchTo
+func (f *Filter) SwitchTo(level interface{}) {
+	f.logger.SwitchTo(level)
+}
+
+// Level return the current level of the wrapped Logger
+func (f *Filter) Level() LogLevel {
+	return f.logger.Level()
+}
+
+// LevelName return the current level name of the wrapped Logger
+func (f *Filter) LevelName() string {
+	return f.logger.LevelName()
+}
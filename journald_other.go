@@ -0,0 +1,13 @@
+//go:build !linux
+
+package simplexlog
+
+import (
+	"errors"
+	"io"
+)
+
+// NewJournaldWriter is only supported on Linux; on other platforms it always returns an error.
+func NewJournaldWriter(fields map[string]string) (io.Writer, error) {
+	return nil, errors.New("simplexlog: NewJournaldWriter is only supported on linux")
+}
@@ -0,0 +1,8 @@
+package simplexlog
+
+// LeveledWriter is implemented by sinks that need a record's LogLevel, e.g. to pick a syslog severity.
+// Logger calls WriteLevel when the writer configured with SetOutput/Config.Out implements it, falling back
+// to plain Write otherwise.
+type LeveledWriter interface {
+	WriteLevel(level LogLevel, p []byte) (int, error)
+}
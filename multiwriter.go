@@ -0,0 +1,50 @@
+package simplexlog
+
+import "io"
+
+// multiWriter fans out to a set of writers independently: a failing writer does not stop the others, and
+// its error, if any, is reported to onError. It implements LeveledWriter so a leveled sink (e.g. one from
+// NewSyslogWriter) fanned out to still gets the right severity.
+type multiWriter struct {
+	writers []io.Writer
+	onError func(w io.Writer, err error)
+}
+
+// MultiWriter return a writer that fans out every write to each of ws independently: a write failing on
+// one writer does not stop it being attempted on the others. See MultiWriterWithErrorHandler to observe
+// per-writer errors.
+func MultiWriter(ws ...io.Writer) io.Writer {
+	return MultiWriterWithErrorHandler(nil, ws...)
+}
+
+// MultiWriterWithErrorHandler is MultiWriter, reporting every per-writer error to onError (if non-nil)
+// instead of silently swallowing it.
+func MultiWriterWithErrorHandler(onError func(w io.Writer, err error), ws ...io.Writer) io.Writer {
+	return &multiWriter{writers: ws, onError: onError}
+}
+
+func (m *multiWriter) Write(p []byte) (int, error) {
+	for _, w := range m.writers {
+		if _, err := w.Write(p); err != nil && m.onError != nil {
+			m.onError(w, err)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (m *multiWriter) WriteLevel(level LogLevel, p []byte) (int, error) {
+	for _, w := range m.writers {
+		var err error
+		if lw, ok := w.(LeveledWriter); ok {
+			_, err = lw.WriteLevel(level, p)
+		} else {
+			_, err = w.Write(p)
+		}
+		if err != nil && m.onError != nil {
+			m.onError(w, err)
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,201 @@
+package simplexlog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// loggerEntry tracks a registered Logger together with whether its level was explicitly set with
+// SetPackageLevel, in which case it no longer follows SetGlobalLevel.
+type loggerEntry struct {
+	logger     *Logger
+	overridden bool
+}
+
+// registry is the process-wide set of named loggers created with NewPackageLogger/MustRepoLogger, modeled
+// on capnslog's repo/package logger.
+type registry struct {
+	mutex       sync.Mutex
+	globalLevel LogLevel
+	loggers     map[string]*loggerEntry
+}
+
+var defaultRegistry = &registry{
+	globalLevel: Info,
+	loggers:     make(map[string]*loggerEntry),
+}
+
+// UnknownLoggerError reports a SetPackageLevel/ParseLogLevelConfig name that was never registered with
+// NewPackageLogger or MustRepoLogger.
+type UnknownLoggerError struct {
+	Name string
+}
+
+func (e *UnknownLoggerError) Error() string {
+	return fmt.Sprintf("simplexlog: unknown logger %q", e.Name)
+}
+
+// InvalidLevelError reports a ParseLogLevelConfig level that doesn't match any LogLevel name.
+type InvalidLevelError struct {
+	Level string
+}
+
+func (e *InvalidLevelError) Error() string {
+	return fmt.Sprintf("simplexlog: invalid log level %q", e.Level)
+}
+
+// loggerName builds the registry key for a repo/pkg pair, pkg may be empty for a repo-level logger
+func loggerName(repo, pkg string) string {
+	if pkg == "" {
+		return repo
+	}
+
+	return repo + "/" + pkg
+}
+
+// NewPackageLogger return the Logger registered for repo/pkg, creating it, at the current global level,
+// on first use. Calling NewPackageLogger again with the same repo/pkg returns the same Logger.
+func NewPackageLogger(repo, pkg string) *Logger {
+	return defaultRegistry.getOrCreate(loggerName(repo, pkg))
+}
+
+// MustRepoLogger return the repo-level Logger for repo, creating it on first use. It panics if repo is empty.
+func MustRepoLogger(repo string) *Logger {
+	if strings.TrimSpace(repo) == "" {
+		panic("simplexlog: repo name must not be empty")
+	}
+
+	return defaultRegistry.getOrCreate(loggerName(repo, ""))
+}
+
+func (r *registry) getOrCreate(name string) *Logger {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if entry, ok := r.loggers[name]; ok {
+		return entry.logger
+	}
+
+	l := New()
+	l.SwitchTo(r.globalLevel)
+	r.loggers[name] = &loggerEntry{logger: l}
+
+	return l
+}
+
+// SetGlobalLevel change the level of every registered logger that hasn't been given its own level with
+// SetPackageLevel, and become the level new loggers are registered at.
+func SetGlobalLevel(level LogLevel) {
+	defaultRegistry.mutex.Lock()
+	defer defaultRegistry.mutex.Unlock()
+
+	defaultRegistry.globalLevel = level
+	for _, entry := range defaultRegistry.loggers {
+		if !entry.overridden {
+			entry.logger.SwitchTo(level)
+		}
+	}
+}
+
+// SetPackageLevel set the level of the registered repo/pkg logger, overriding SetGlobalLevel for it. It
+// returns an *UnknownLoggerError if repo/pkg was never registered with NewPackageLogger/MustRepoLogger.
+func SetPackageLevel(repo, pkg string, level LogLevel) error {
+	return setLevelByName(loggerName(repo, pkg), level)
+}
+
+func setLevelByName(name string, level LogLevel) error {
+	defaultRegistry.mutex.Lock()
+	defer defaultRegistry.mutex.Unlock()
+
+	entry, ok := defaultRegistry.loggers[name]
+	if !ok {
+		return &UnknownLoggerError{Name: name}
+	}
+
+	entry.logger.SwitchTo(level)
+	entry.overridden = true
+
+	return nil
+}
+
+// ParseLogLevelConfig parses a comma-separated spec like "repo/pkgA=DEBUG,repo/pkgB=ERROR,*=INFO" and applies
+// it atomically: either every entry is valid and applied, or none are and an error is returned. The name "*"
+// sets the global level (see SetGlobalLevel); any other name must already be registered (see SetPackageLevel).
+func ParseLogLevelConfig(spec string) error {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil
+	}
+
+	type action struct {
+		name  string
+		level LogLevel
+	}
+
+	var actions []action
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("simplexlog: invalid log level entry %q, want name=LEVEL", part)
+		}
+
+		name := strings.TrimSpace(kv[0])
+		level, ok := levelByName(kv[1])
+		if !ok {
+			return &InvalidLevelError{Level: strings.TrimSpace(kv[1])}
+		}
+
+		if name != "*" {
+			defaultRegistry.mutex.Lock()
+			_, known := defaultRegistry.loggers[name]
+			defaultRegistry.mutex.Unlock()
+			if !known {
+				return &UnknownLoggerError{Name: name}
+			}
+		}
+
+		actions = append(actions, action{name: name, level: level})
+	}
+
+	for _, a := range actions {
+		if a.name == "*" {
+			SetGlobalLevel(a.level)
+			continue
+		}
+
+		if err := setLevelByName(a.name, a.level); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoggerStatus describe a registered logger and its current level, as returned by LoggerInfo.
+type LoggerStatus struct {
+	Name  string
+	Level LogLevel
+}
+
+// LoggerInfo return the name and current level of every registered logger, sorted by name, so a CLI
+// --log-level flag can report or drive the whole process.
+func LoggerInfo() []LoggerStatus {
+	defaultRegistry.mutex.Lock()
+	defer defaultRegistry.mutex.Unlock()
+
+	statuses := make([]LoggerStatus, 0, len(defaultRegistry.loggers))
+	for name, entry := range defaultRegistry.loggers {
+		statuses = append(statuses, LoggerStatus{Name: name, Level: entry.logger.Level()})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	return statuses
+}
@@ -0,0 +1,330 @@
+package simplexlog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOptions configure a RotatingFile, see NewRotatingFile
+type RotateOptions struct {
+	// MaxSizeBytes rotates the file once it would grow past this size, 0 disables size-based rotation
+	MaxSizeBytes int64
+	// MaxAgeDuration rotates the file once it has been open this long (for a file that already existed when
+	// first opened, counted from its modification time), and prunes backups older than this, 0 disables both
+	MaxAgeDuration time.Duration
+	// MaxBackups caps the number of rotated backups kept, oldest first, 0 keeps them all
+	MaxBackups int
+	// Compress gzips a backup after it is rotated out
+	Compress bool
+	// LocalTime names backups using local time instead of UTC
+	LocalTime bool
+	// Mode is the file mode used to create the log file and its backups, default 0644
+	Mode os.FileMode
+}
+
+// RotatingFile is an io.WriteCloser that writes to path, rotating it to a timestamped backup
+// (name-YYYYMMDD-HHMMSS.log[.gz]) on a size or time boundary, whichever comes first. Writes are safe for
+// concurrent use. Compression and retention of old backups run on a background goroutine; Close drains it.
+type RotatingFile struct {
+	Path string
+	Opts RotateOptions
+
+	mutex    sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	backups   chan string
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewRotatingFile return a RotatingFile for path. The file is opened (or created) lazily on the first Write.
+func NewRotatingFile(path string, opts RotateOptions) *RotatingFile {
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+
+	rf := &RotatingFile{
+		Path:    path,
+		Opts:    opts,
+		backups: make(chan string, 64),
+	}
+
+	rf.wg.Add(1)
+	go rf.backgroundWorker()
+
+	return rf
+}
+
+// Write implements io.Writer, rotating the file first if p would push it past a configured boundary
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if rf.file == nil {
+		if err := rf.openExistingOrNew(); err != nil {
+			return 0, err
+		}
+	}
+
+	if rf.needsRotationLocked(int64(len(p))) {
+		if err := rf.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+
+	return n, err
+}
+
+// Rotate forces a rotation now, regardless of the configured size/age boundaries
+func (rf *RotatingFile) Rotate() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	return rf.rotateLocked()
+}
+
+// Close closes the current file and waits for pending compression/retention work to finish
+func (rf *RotatingFile) Close() error {
+	rf.mutex.Lock()
+	var err error
+	if rf.file != nil {
+		err = rf.file.Close()
+		rf.file = nil
+	}
+	rf.mutex.Unlock()
+
+	rf.closeOnce.Do(func() { close(rf.backups) })
+	rf.wg.Wait()
+
+	return err
+}
+
+func (rf *RotatingFile) needsRotationLocked(n int64) bool {
+	if rf.Opts.MaxSizeBytes > 0 && rf.size+n > rf.Opts.MaxSizeBytes {
+		return true
+	}
+
+	return rf.Opts.MaxAgeDuration > 0 && time.Since(rf.openedAt) >= rf.Opts.MaxAgeDuration
+}
+
+func (rf *RotatingFile) openExistingOrNew() error {
+	info, err := os.Stat(rf.Path)
+	if os.IsNotExist(err) {
+		return rf.openNewLocked()
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(rf.Path, os.O_APPEND|os.O_WRONLY, rf.Opts.Mode)
+	if err != nil {
+		return err
+	}
+
+	rf.file = f
+	rf.size = info.Size()
+	rf.openedAt = info.ModTime()
+
+	return nil
+}
+
+func (rf *RotatingFile) openNewLocked() error {
+	f, err := os.OpenFile(rf.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, rf.Opts.Mode)
+	if err != nil {
+		return err
+	}
+
+	rf.file = f
+	rf.size = 0
+	rf.openedAt = time.Now()
+
+	return nil
+}
+
+func (rf *RotatingFile) rotateLocked() error {
+	if rf.file != nil {
+		rf.file.Close()
+		rf.file = nil
+	}
+
+	now := time.Now()
+	if !rf.Opts.LocalTime {
+		now = now.UTC()
+	}
+
+	if _, err := os.Stat(rf.Path); err == nil {
+		backup := uniqueBackupName(rf.Path, now)
+		if err := os.Rename(rf.Path, backup); err != nil {
+			return err
+		}
+		rf.enqueueBackup(backup)
+	}
+
+	return rf.openNewLocked()
+}
+
+// enqueueBackup hands a freshly rotated backup to the background worker, for compression and/or retention
+func (rf *RotatingFile) enqueueBackup(path string) {
+	if !rf.Opts.Compress && rf.Opts.MaxBackups <= 0 && rf.Opts.MaxAgeDuration <= 0 {
+		return
+	}
+
+	rf.backups <- path
+}
+
+// backupName formats the backup path for path rotated at t, distinguished by seq (0 for the first
+// rotation within a given second, incrementing for any further collision, see uniqueBackupName).
+func backupName(path string, t time.Time, seq int) string {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext)
+
+	if seq == 0 {
+		return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, t.Format("20060102-150405"), ext))
+	}
+
+	return filepath.Join(dir, fmt.Sprintf("%s-%s-%d%s", prefix, t.Format("20060102-150405"), seq, ext))
+}
+
+// uniqueBackupName returns a backupName for path rotated at t that does not already exist, appending a
+// collision counter when bursty size-based rotations land within the same second-resolution timestamp.
+func uniqueBackupName(path string, t time.Time) string {
+	for seq := 0; ; seq++ {
+		name := backupName(path, t, seq)
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			return name
+		}
+	}
+}
+
+func (rf *RotatingFile) backgroundWorker() {
+	defer rf.wg.Done()
+
+	for path := range rf.backups {
+		if rf.Opts.Compress {
+			if compressed, err := gzipFile(path, rf.Opts.Mode); err == nil {
+				path = compressed
+			}
+		}
+		rf.enforceRetention()
+	}
+}
+
+// gzipFile compresses path to path+".gz" (created with mode), removing the original on success, and returns
+// the new path
+func gzipFile(path string, mode os.FileMode) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dstPath := path + ".gz"
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return "", err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(dstPath)
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dstPath)
+		return "", err
+	}
+
+	os.Remove(path)
+
+	return dstPath, nil
+}
+
+// backupEntry is a rotated backup file found by enforceRetention, paired with its modification time so
+// retention can prune oldest-first without relying on the filename sorting lexically by age.
+type backupEntry struct {
+	path    string
+	modTime time.Time
+}
+
+// enforceRetention prunes backups of rf.Path beyond Opts.MaxBackups and/or older than Opts.MaxAgeDuration
+func (rf *RotatingFile) enforceRetention() {
+	if rf.Opts.MaxBackups <= 0 && rf.Opts.MaxAgeDuration <= 0 {
+		return
+	}
+
+	dir := filepath.Dir(rf.Path)
+	base := filepath.Base(rf.Path)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var backups []backupEntry
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || !(strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz")) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupEntry{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+
+	// Sort oldest first by modification time, not filename: a same-second collision backup
+	// (name-<ts>-1.log) sorts lexically before its seq-0 sibling (name-<ts>.log) since '-' < '.', which
+	// would otherwise make the newer of the two look older to the pruning below.
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.Before(backups[j].modTime) })
+
+	if rf.Opts.MaxAgeDuration > 0 {
+		cutoff := time.Now().Add(-rf.Opts.MaxAgeDuration)
+		kept := backups[:0]
+		for _, b := range backups {
+			if b.modTime.Before(cutoff) {
+				os.Remove(b.path)
+				continue
+			}
+			kept = append(kept, b)
+		}
+		backups = kept
+	}
+
+	if rf.Opts.MaxBackups > 0 && len(backups) > rf.Opts.MaxBackups {
+		for _, b := range backups[:len(backups)-rf.Opts.MaxBackups] {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// SetFileOutput set the output of the given level (or All) to a rotating file at path, see NewRotatingFile
+func SetFileOutput(level LogLevel, path string, opts RotateOptions) func(*Logger) {
+	return SetOutput(level, NewRotatingFile(path, opts))
+}
@@ -0,0 +1,42 @@
+package simplexlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEnforceRetentionOrdersBySameSecondCollision checks that retention prunes oldest-first by modification
+// time even when two backups collide on the same second-resolution timestamp: the seq-1 disambiguator
+// ("name-<ts>-1.log") sorts lexically BEFORE the seq-0 name ("name-<ts>.log") since '-' < '.', so a naive
+// filename sort would prune the newer of the two.
+func TestEnforceRetentionOrdersBySameSecondCollision(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	now := time.Now().UTC()
+	oldest := backupName(path, now, 0)
+	newest := backupName(path, now, 1)
+
+	for _, name := range []string{oldest, newest} {
+		if err := os.WriteFile(name, []byte("x"), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	olderTime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(oldest, olderTime, olderTime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	rf := &RotatingFile{Path: path, Opts: RotateOptions{MaxBackups: 1}}
+	rf.enforceRetention()
+
+	if _, err := os.Stat(newest); err != nil {
+		t.Fatalf("expected the newer collision backup to survive retention: %v", err)
+	}
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Fatalf("expected the older backup to be pruned, stat err = %v", err)
+	}
+}
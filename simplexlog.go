@@ -3,12 +3,15 @@
 package simplexlog
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -59,6 +62,24 @@ const (
 // LogLevel level of log
 type LogLevel uint
 
+// Format selects how a log record is rendered
+type Format uint
+
+const (
+	// TextFormat renders records with the standard log package text layout (the default)
+	TextFormat Format = iota
+	// JSONFormat renders records as a single JSON line with keys sorted, e.g.
+	// {"foo":"bar","level":"INFO","msg":"...","ts":"..."}
+	JSONFormat
+)
+
+// levelState holds the mutable log level, shared by a Logger and every child derived from it with Fields/With,
+// so that SwitchTo on the parent is immediately visible to its children.
+type levelState struct {
+	mutex sync.Mutex // guard the log level
+	level LogLevel
+}
+
 // Logger simple log wrapper
 type Logger struct {
 	logCritical,
@@ -68,8 +89,15 @@ type Logger struct {
 	logInfo,
 	logDebug,
 	logTrace *log.Logger
-	mutex sync.Mutex // guard the log level
-	level LogLevel
+	state  *levelState
+	format Format
+	fields Fields
+
+	includeCaller bool
+	callerSkip    int
+	stackTraces   bool
+
+	async *asyncDispatcher
 }
 
 // Config log option
@@ -80,6 +108,23 @@ type Config struct {
 	Label string
 	// Flags are the same combination of flag of standard log package
 	Flags int
+	// Format selects the rendering of the log line, TextFormat (default) or JSONFormat
+	Format Format
+	// IncludeCaller prepends the caller's file:line to every log line, computed with runtime.Caller since
+	// log.Logger's own Lshortfile/Llongfile flags would point at simplexlog instead of the caller
+	IncludeCaller bool
+	// CallerSkip adjusts the number of additional stack frames to skip when IncludeCaller is set, for callers
+	// that wrap Logger in their own helper functions
+	CallerSkip int
+	// StackTraces captures a bounded runtime.Stack snapshot and appends it to Error/Critical/Fatal/Panic records
+	StackTraces bool
+	// Async, when true, makes the Logger enqueue records onto a BufferSize-bounded channel drained by a
+	// background goroutine, instead of blocking the caller on the underlying writer. See Flush/Close/Dropped.
+	Async bool
+	// BufferSize is the Async channel capacity, default 1 when Async is true and BufferSize <= 0
+	BufferSize int
+	// DropPolicy selects what happens to a record when the Async buffer is full, default Block
+	DropPolicy DropPolicy
 }
 
 // SetDefault set the options of default logger used by all log level except Error, Critical, Fatal and Panic
@@ -90,6 +135,10 @@ func SetDefault(c Config) func(*Logger) {
 		l.logNotice = l.logWarning
 		l.logDebug = l.logWarning
 		l.logTrace = l.logWarning
+		l.format = c.Format
+		l.includeCaller = c.IncludeCaller
+		l.callerSkip = c.CallerSkip
+		l.applyAsync(c)
 	}
 }
 
@@ -98,6 +147,11 @@ func SetErrorDefault(c Config) func(*Logger) {
 	return func(l *Logger) {
 		l.logError = log.New(c.Out, c.Label, c.Flags)
 		l.logCritical = l.logError
+		l.format = c.Format
+		l.includeCaller = c.IncludeCaller
+		l.callerSkip = c.CallerSkip
+		l.stackTraces = c.StackTraces
+		l.applyAsync(c)
 	}
 }
 
@@ -111,6 +165,11 @@ func SetAllDefault(c Config) func(*Logger) {
 		l.logTrace = l.logWarning
 		l.logError = l.logWarning
 		l.logCritical = l.logWarning
+		l.format = c.Format
+		l.includeCaller = c.IncludeCaller
+		l.callerSkip = c.CallerSkip
+		l.stackTraces = c.StackTraces
+		l.applyAsync(c)
 	}
 }
 
@@ -204,7 +263,7 @@ func New(configurations ...func(*Logger)) *Logger {
 		logInfo:     log.New(os.Stdout, fmt.Sprintf("%-9s", LevelInfo), DefaultLogFlags),
 		logDebug:    log.New(os.Stdout, fmt.Sprintf("%-9s", LevelDebug), DefaultLogFlags),
 		logTrace:    log.New(os.Stdout, fmt.Sprintf("%-9s", LevelTrace), DefaultLogFlags),
-		level:       Info,
+		state:       &levelState{level: Info},
 	}
 
 	// now customize logger
@@ -231,35 +290,46 @@ func (l *Logger) switchTo(level LogLevel) {
 		return
 	}
 
-	l.mutex.Lock()
-	l.level = level
-	l.mutex.Unlock()
+	l.state.mutex.Lock()
+	l.state.level = level
+	l.state.mutex.Unlock()
 }
 
 // switchToLevel change log level, must match (case insensitive) level name (like LevelTrace, LevelCritical etc)
 func (l *Logger) switchToLevel(level string) {
-	level = strings.TrimSpace(strings.ToUpper(level))
+	lvl, ok := levelByName(level)
+	if !ok {
+		return
+	}
 
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	switch level {
+	l.state.mutex.Lock()
+	l.state.level = lvl
+	l.state.mutex.Unlock()
+}
+
+// levelByName returns the LogLevel matching name (case insensitive, like LevelTrace, LevelCritical etc), and
+// whether name matched a known level
+func levelByName(name string) (LogLevel, bool) {
+	switch strings.TrimSpace(strings.ToUpper(name)) {
 	case LevelCritical:
-		l.level = Critical
+		return Critical, true
 	case LevelError:
-		l.level = Error
+		return Error, true
 	case LevelWarning:
-		l.level = Warning
+		return Warning, true
 	case LevelNotice:
-		l.level = Notice
+		return Notice, true
 	case LevelInfo:
-		l.level = Info
+		return Info, true
 	case LevelDebug:
-		l.level = Debug
+		return Debug, true
 	case LevelTrace:
-		l.level = Trace
+		return Trace, true
 	case LevelAll:
-		l.level = All
+		return All, true
 	}
+
+	return 0, false
 }
 
 // SetOutput set the output destination for a specified log level
@@ -292,16 +362,16 @@ func (l *Logger) SetOutput(level LogLevel, w io.Writer) {
 
 // Level return the current log level
 func (l *Logger) Level() LogLevel {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	return l.level
+	l.state.mutex.Lock()
+	defer l.state.mutex.Unlock()
+	return l.state.level
 }
 
 // LevelName return the current level name
 func (l *Logger) LevelName() string {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	switch l.level {
+	l.state.mutex.Lock()
+	defer l.state.mutex.Unlock()
+	switch l.state.level {
 	case Critical:
 		return LevelCritical
 	case Error:
@@ -338,124 +408,269 @@ func (l *Logger) LevelNames() string {
 		", ")
 }
 
+// output writes msg to logger, rendering it (with the logger's fields) as a JSON record when Format is JSONFormat,
+// or passing it through unchanged otherwise. When IncludeCaller is set the caller's file:line is prepended;
+// when StackTraces is set a bounded stack snapshot is appended for level Error and Critical (which also covers
+// Fatal/Panic, both logged through logCritical). A JSONFormat record is written straight to logger's
+// underlying writer rather than through logger.Output, since log.Logger would otherwise prepend its own
+// Prefix/Flags (set by whichever Set* configured this level) ahead of the already self-contained JSON line.
+// In Async mode a TextFormat record is likewise written straight to the underlying writer, with logger's own
+// date/time header rendered here at call time and baked into rendered, rather than left to logger.Output to
+// compute when the drain goroutine eventually gets to it.
+func (l *Logger) output(logger *log.Logger, level LogLevel, levelName, msg string) {
+	if l.includeCaller {
+		if caller, ok := l.caller(); ok {
+			msg = caller + ": " + msg
+		}
+	}
+
+	if l.stackTraces && (level == Error || level == Critical) {
+		msg += "\n" + captureStack()
+	}
+
+	rendered := l.textLine(msg)
+	if l.format == JSONFormat {
+		rendered = l.jsonLine(levelName, msg)
+	}
+
+	async := l.async != nil
+	if async && l.format != JSONFormat {
+		rendered = textHeader(logger, time.Now()) + rendered
+	}
+
+	write := func() {
+		if lw, ok := logger.Writer().(LeveledWriter); ok {
+			lw.WriteLevel(level, []byte(rendered))
+			return
+		}
+		if l.format == JSONFormat || async {
+			// Bypass log.Logger.Output: for JSON it would prepend its own Prefix/Flags (e.g. "ERROR    "
+			// plus a second timestamp) ahead of the already self-contained JSON line; for Async text it
+			// would stamp the date/time header at drain time instead of the call-time header baked into
+			// rendered above.
+			fmt.Fprintln(logger.Writer(), rendered)
+			return
+		}
+		logger.Output(3, rendered)
+	}
+
+	if l.async != nil {
+		l.async.enqueue(write)
+		return
+	}
+	write()
+}
+
+// textHeader replicates the portion of log.Logger's own header formatting driven by Prefix and the
+// Ldate/Ltime/Lmicroseconds/LUTC/Lmsgprefix flags, computed at t instead of at logger.Output's call time.
+// Async mode uses this to materialize a TextFormat record's timestamp when it is logged rather than when
+// the drain goroutine eventually writes it. Lshortfile/Llongfile are not reproduced here: as caller.go
+// explains, those flags would point at simplexlog's own output method rather than the caller anyway, so
+// IncludeCaller/CallerSkip should be used instead.
+func textHeader(logger *log.Logger, t time.Time) string {
+	flags := logger.Flags()
+	prefix := logger.Prefix()
+
+	var b strings.Builder
+	if prefix != "" && flags&log.Lmsgprefix == 0 {
+		b.WriteString(prefix)
+	}
+
+	if flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		if flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if flags&log.Ldate != 0 {
+			year, month, day := t.Date()
+			fmt.Fprintf(&b, "%04d/%02d/%02d ", year, month, day)
+		}
+		if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+			hour, min, sec := t.Clock()
+			fmt.Fprintf(&b, "%02d:%02d:%02d", hour, min, sec)
+			if flags&log.Lmicroseconds != 0 {
+				fmt.Fprintf(&b, ".%06d", t.Nanosecond()/1e3)
+			}
+			b.WriteString(" ")
+		}
+	}
+
+	if flags&log.Lmsgprefix != 0 {
+		b.WriteString(prefix)
+	}
+
+	return b.String()
+}
+
+// jsonLine renders msg and the logger's fields as a single JSON line
+func (l *Logger) jsonLine(levelName, msg string) string {
+	rec := make(map[string]interface{}, len(l.fields)+3)
+	for k, v := range l.fields {
+		rec[k] = v
+	}
+	rec["level"] = levelName
+	rec["ts"] = time.Now().Format(time.RFC3339Nano)
+	rec["msg"] = msg
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return msg
+	}
+
+	return string(b)
+}
+
+// textLine appends the logger's fields, sorted by key, as "key=value" pairs after msg
+func (l *Logger) textLine(msg string) string {
+	if len(l.fields) == 0 {
+		return msg
+	}
+
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, l.fields[k])
+	}
+
+	return b.String()
+}
+
 // Infof print, accordind to format, to the Info logger
 func (l *Logger) Infof(format string, v ...interface{}) {
 	if l.Level() >= Info {
-		l.logInfo.Printf(format, v...)
+		l.output(l.logInfo, Info, LevelInfo, fmt.Sprintf(format, v...))
 	}
 }
 
 // Noticef print, accordind to format, to the Notice logger
 func (l *Logger) Noticef(format string, v ...interface{}) {
 	if l.Level() >= Notice {
-		l.logNotice.Printf(format, v...)
+		l.output(l.logNotice, Notice, LevelNotice, fmt.Sprintf(format, v...))
 	}
 }
 
 // Warningf print, accordind to format, to the Warning logger
 func (l *Logger) Warningf(format string, v ...interface{}) {
 	if l.Level() >= Warning {
-		l.logWarning.Printf(format, v...)
+		l.output(l.logWarning, Warning, LevelWarning, fmt.Sprintf(format, v...))
 	}
 }
 
 // Errorf print, accordind to format, to the Error logger
 func (l *Logger) Errorf(format string, v ...interface{}) {
 	if l.Level() >= Error {
-		l.logError.Printf(format, v...)
+		l.output(l.logError, Error, LevelError, fmt.Sprintf(format, v...))
 	}
 }
 
 // Criticalf print, accordind to format, to the Critical logger
 func (l *Logger) Criticalf(format string, v ...interface{}) {
 	if l.Level() >= Critical {
-		l.logCritical.Printf(format, v...)
+		l.output(l.logCritical, Critical, LevelCritical, fmt.Sprintf(format, v...))
 	}
 }
 
 // Debugf print, accordind to format, to the Debug logger
 func (l *Logger) Debugf(format string, v ...interface{}) {
 	if l.Level() >= Debug {
-		l.logDebug.Printf(format, v...)
+		l.output(l.logDebug, Debug, LevelDebug, fmt.Sprintf(format, v...))
 	}
 }
 
 // Tracef print, accordind to format, to the Debug logger
 func (l *Logger) Tracef(format string, v ...interface{}) {
 	if l.Level() >= Trace {
-		l.logTrace.Printf(format, v...)
+		l.output(l.logTrace, Trace, LevelTrace, fmt.Sprintf(format, v...))
 	}
 }
 
-// Fatalf print fatal message, accordind to format, to critical logger, followed by call to os.Exit(1)
+// Fatalf print fatal message, accordind to format, to critical logger, followed by call to os.Exit(1). In
+// Async mode the record is flushed before exiting, otherwise it would never reach the background goroutine.
 func (l *Logger) Fatalf(format string, v ...interface{}) {
-	l.logCritical.Fatalf(format, v...)
-
+	l.output(l.logCritical, Critical, LevelCritical, fmt.Sprintf(format, v...))
+	l.flushAsync()
+	os.Exit(1)
 }
 
-// Panicf print panic message to the critical logger, followed by call to panic()
+// Panicf print panic message to the critical logger, followed by call to panic(). In Async mode the record
+// is flushed before panicking, otherwise it would never reach the background goroutine.
 func (l *Logger) Panicf(format string, v ...interface{}) {
-	l.logCritical.Panicf(format, v...)
+	msg := fmt.Sprintf(format, v...)
+	l.output(l.logCritical, Critical, LevelCritical, msg)
+	l.flushAsync()
+	panic(msg)
 }
 
 // Info print to the Info logger
 func (l *Logger) Info(a ...interface{}) {
 	if l.Level() >= Info {
-		l.logInfo.Print(a...)
+		l.output(l.logInfo, Info, LevelInfo, fmt.Sprint(a...))
 	}
 }
 
 // Notice print to the Notice logger
 func (l *Logger) Notice(a ...interface{}) {
 	if l.Level() >= Notice {
-		l.logNotice.Print(a...)
+		l.output(l.logNotice, Notice, LevelNotice, fmt.Sprint(a...))
 	}
 }
 
 // Warning print to the Warning logger
 func (l *Logger) Warning(a ...interface{}) {
 	if l.Level() >= Warning {
-		l.logWarning.Print(a...)
+		l.output(l.logWarning, Warning, LevelWarning, fmt.Sprint(a...))
 	}
 }
 
 // Error print to the Error logger
 func (l *Logger) Error(a ...interface{}) {
 	if l.Level() >= Error {
-		l.logError.Print(a...)
+		l.output(l.logError, Error, LevelError, fmt.Sprint(a...))
 	}
 }
 
 // Critical print to the Critical logger
 func (l *Logger) Critical(a ...interface{}) {
 	if l.Level() >= Critical {
-		l.logCritical.Print(a...)
+		l.output(l.logCritical, Critical, LevelCritical, fmt.Sprint(a...))
 	}
 }
 
 // Debug print to the Debug logger
 func (l *Logger) Debug(a ...interface{}) {
 	if l.Level() >= Debug {
-		l.logDebug.Print(a...)
+		l.output(l.logDebug, Debug, LevelDebug, fmt.Sprint(a...))
 	}
 }
 
 // Trace print to the Debug logger
 func (l *Logger) Trace(a ...interface{}) {
 	if l.Level() >= Trace {
-		l.logTrace.Print(a...)
+		l.output(l.logTrace, Trace, LevelTrace, fmt.Sprint(a...))
 	}
 }
 
-// Fatal print fatal message to critical logger, followed by call to os.Exit(1)
+// Fatal print fatal message to critical logger, followed by call to os.Exit(1). In Async mode the record
+// is flushed before exiting, otherwise it would never reach the background goroutine.
 func (l *Logger) Fatal(a ...interface{}) {
-	l.logCritical.Fatal(a...)
-
+	msg := fmt.Sprint(a...)
+	l.output(l.logCritical, Critical, LevelCritical, msg)
+	l.flushAsync()
+	os.Exit(1)
 }
 
-// Panic print panic message to the critical logger, followed by call to panic()
+// Panic print panic message to the critical logger, followed by call to panic(). In Async mode the record
+// is flushed before panicking, otherwise it would never reach the background goroutine.
 func (l *Logger) Panic(a ...interface{}) {
-	l.logCritical.Panic(a...)
+	msg := fmt.Sprint(a...)
+	l.output(l.logCritical, Critical, LevelCritical, msg)
+	l.flushAsync()
+	panic(msg)
 }
 
 // InfoLogger return the info logger
@@ -0,0 +1,146 @@
+//go:build !windows && !plan9
+
+package simplexlog
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// SyslogFormat selects the wire format used by NewSyslogWriter
+type SyslogFormat int
+
+const (
+	// RFC3164Format is the classic BSD syslog format (the default), sent via the standard library's log/syslog
+	RFC3164Format SyslogFormat = iota
+	// RFC5424Format is the newer IETF syslog format; simplexlog dials and frames it itself, since the
+	// standard library only speaks RFC3164 on the wire
+	RFC5424Format
+)
+
+// syslogSeverity maps a simplexlog LogLevel to the matching syslog severity
+func syslogSeverity(level LogLevel) syslog.Priority {
+	switch level {
+	case Critical:
+		return syslog.LOG_CRIT
+	case Error:
+		return syslog.LOG_ERR
+	case Warning:
+		return syslog.LOG_WARNING
+	case Notice:
+		return syslog.LOG_NOTICE
+	case Info:
+		return syslog.LOG_INFO
+	default: // Debug, Trace, All
+		return syslog.LOG_DEBUG
+	}
+}
+
+// NewSyslogWriter return a LeveledWriter/io.Writer sink that forwards records to a syslog daemon at
+// network/addr (network="" dials the local syslog), tagged tag, under facility (0-23, see RFC3164 4.1.1).
+// format selects RFC3164Format (the default) or RFC5424Format. The local-syslog default of network=""
+// applies to RFC3164Format only: RFC5424Format dials network/addr as given and fails on an empty network.
+func NewSyslogWriter(network, addr, tag string, facility int, format SyslogFormat) (io.Writer, error) {
+	if format == RFC5424Format {
+		return newRFC5424Writer(network, addr, tag, facility)
+	}
+
+	w, err := syslog.Dial(network, addr, syslog.Priority(facility<<3)|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rfc3164Writer{w: w}, nil
+}
+
+// rfc3164Writer adapts a *syslog.Writer, which always speaks RFC3164 on the wire, to LeveledWriter by
+// picking the severity-specific method matching the record's level
+type rfc3164Writer struct {
+	w *syslog.Writer
+}
+
+func (r *rfc3164Writer) Write(p []byte) (int, error) {
+	return r.w.Write(p)
+}
+
+func (r *rfc3164Writer) WriteLevel(level LogLevel, p []byte) (int, error) {
+	msg := string(p)
+
+	var err error
+	switch level {
+	case Critical:
+		err = r.w.Crit(msg)
+	case Error:
+		err = r.w.Err(msg)
+	case Warning:
+		err = r.w.Warning(msg)
+	case Notice:
+		err = r.w.Notice(msg)
+	case Info:
+		err = r.w.Info(msg)
+	default: // Debug, Trace, All
+		err = r.w.Debug(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// rfc5424Writer is a minimal RFC5424 syslog sink, dialing and framing the message itself
+type rfc5424Writer struct {
+	mutex    sync.Mutex
+	conn     net.Conn
+	tag      string
+	facility int
+	hostname string
+	pid      int
+}
+
+func newRFC5424Writer(network, addr, tag string, facility int) (*rfc5424Writer, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+
+	return &rfc5424Writer{
+		conn:     conn,
+		tag:      tag,
+		facility: facility,
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}, nil
+}
+
+func (r *rfc5424Writer) writeSeverity(severity syslog.Priority, p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	frame := fmt.Sprintf("<%d>1 %s %s %s %d - - %s\n",
+		r.facility<<3|int(severity), time.Now().UTC().Format(time.RFC3339), r.hostname, r.tag, r.pid, p)
+
+	if _, err := io.WriteString(r.conn, frame); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (r *rfc5424Writer) Write(p []byte) (int, error) {
+	return r.writeSeverity(syslog.LOG_INFO, p)
+}
+
+func (r *rfc5424Writer) WriteLevel(level LogLevel, p []byte) (int, error) {
+	return r.writeSeverity(syslogSeverity(level), p)
+}
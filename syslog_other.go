@@ -0,0 +1,24 @@
+//go:build windows || plan9
+
+package simplexlog
+
+import (
+	"errors"
+	"io"
+)
+
+// SyslogFormat selects the wire format used by NewSyslogWriter
+type SyslogFormat int
+
+const (
+	// RFC3164Format is the classic BSD syslog format (the default)
+	RFC3164Format SyslogFormat = iota
+	// RFC5424Format is the newer IETF syslog format
+	RFC5424Format
+)
+
+// NewSyslogWriter is not supported on windows/plan9, which have no syslog daemon (the standard library's
+// log/syslog package itself excludes these platforms).
+func NewSyslogWriter(network, addr, tag string, facility int, format SyslogFormat) (io.Writer, error) {
+	return nil, errors.New("simplexlog: NewSyslogWriter is not supported on this platform")
+}